@@ -0,0 +1,270 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// archiveMemberSeparator joins an archive's path to the name of one of its
+// members to form the synthesized FileAttributes.Name for that member, e.g.
+// "archive.tar!member/path.log".
+const archiveMemberSeparator = "!"
+
+// isArchivePath reports whether path should be handled by the archive reader
+// rather than read directly, based on its extension.
+func isArchivePath(path string) bool {
+	switch {
+	case strings.HasSuffix(path, ".tar"), strings.HasSuffix(path, ".tar.gz"), strings.HasSuffix(path, ".gz"):
+		return true
+	default:
+		return false
+	}
+}
+
+// archiveMember describes a single logical file found inside an archive.
+type archiveMember struct {
+	name string // member name as stored in the archive, e.g. "member/path.log"
+	open func() (io.ReadCloser, error)
+}
+
+// listArchiveMembers opens path and enumerates the logical files it contains.
+// A plain ".gz" file yields a single member whose content is the decompressed
+// stream; a ".tar" or ".tar.gz" file yields one member per regular file entry.
+func listArchiveMembers(path string) ([]archiveMember, error) {
+	if strings.HasSuffix(path, ".tar") || strings.HasSuffix(path, ".tar.gz") {
+		return listTarMembers(path)
+	}
+	// Plain .gz: the whole decompressed stream is a single logical file,
+	// named after the archive's own base name with the .gz suffix removed.
+	name := strings.TrimSuffix(filepath.Base(path), ".gz")
+	return []archiveMember{
+		{
+			name: name,
+			open: func() (io.ReadCloser, error) { return openGzipMember(path) },
+		},
+	}, nil
+}
+
+func openGzipMember(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("open gzip stream: %w", err)
+	}
+	return &readCloserChain{Reader: gz, closers: []io.Closer{gz, f}}, nil
+}
+
+func listTarMembers(path string) ([]archiveMember, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var tr *tar.Reader
+	if strings.HasSuffix(path, ".tar.gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		tr = tar.NewReader(gz)
+	} else {
+		tr = tar.NewReader(f)
+	}
+
+	var members []archiveMember
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar header: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := hdr.Name
+		index := len(members)
+		members = append(members, archiveMember{
+			name: name,
+			open: func() (io.ReadCloser, error) { return openTarMember(path, index) },
+		})
+	}
+	return members, nil
+}
+
+// openTarMember reopens the archive at path and seeks forward to the
+// index'th regular-file entry. Archives don't support random access to a
+// single member, so each call re-scans from the start; this is acceptable
+// because, unlike plain tailed files, an archive member is read exactly
+// once. Matching by index rather than by name avoids misattributing content
+// when a tar archive contains two entries with the same name.
+func openTarMember(path string, index int) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tr *tar.Reader
+	closers := []io.Closer{f}
+	if strings.HasSuffix(path, ".tar.gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("open gzip stream: %w", err)
+		}
+		closers = append([]io.Closer{gz}, closers...)
+		tr = tar.NewReader(gz)
+	} else {
+		tr = tar.NewReader(f)
+	}
+
+	seen := -1
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, fmt.Errorf("member at index %d not found in %q", index, path)
+		}
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, fmt.Errorf("read tar header: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		seen++
+		if seen == index {
+			return &readCloserChain{Reader: tr, closers: closers}, nil
+		}
+	}
+}
+
+// readCloserChain adapts a tar Reader (which has no Close method of its own)
+// or a gzip Reader (whose Close does not close the file beneath it) into an
+// io.ReadCloser that also closes the underlying file and/or gzip stream it
+// was built from.
+type readCloserChain struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloserChain) Close() error {
+	var firstErr error
+	for _, c := range r.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// fingerprintMember reads up to size bytes from r and returns a hex-encoded
+// sha256 digest of that decompressed content prefix, used to detect whether
+// the member has already been consumed. The digest does not depend on the
+// archive's path or the member's name, so a renamed (rotated) archive maps
+// to the same fingerprints; members whose first size bytes are identical
+// share one fingerprint.
+func fingerprintMember(r io.Reader, size int) (string, error) {
+	buf := make([]byte, size)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(buf[:n])
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readArchive enumerates the members of the archive at path and emits the
+// content of any member not already recorded as consumed. Archives are
+// read-once per fingerprint: once a member has been fully read, it is never
+// re-tailed, even if the archive is later rotated or appended to.
+func (m *Manager) readArchive(ctx context.Context, path string) {
+	members, err := listArchiveMembers(path)
+	if err != nil {
+		m.logger.Errorw("failed to list archive members", zap.String("path", path), zap.Error(err))
+		return
+	}
+
+	for _, member := range members {
+		rc, err := member.open()
+		if err != nil {
+			m.logger.Errorw("failed to open archive member", zap.String("path", path), zap.String("member", member.name), zap.Error(err))
+			continue
+		}
+
+		fingerprint, err := fingerprintMember(rc, m.maxLogSize)
+		if err != nil {
+			rc.Close()
+			m.logger.Errorw("failed to fingerprint archive member", zap.String("path", path), zap.String("member", member.name), zap.Error(err))
+			continue
+		}
+
+		m.mu.Lock()
+		_, consumed := m.consumedArchiveMembers[fingerprint]
+		if !consumed {
+			m.consumedArchiveMembers[fingerprint] = struct{}{}
+		}
+		m.mu.Unlock()
+		if consumed {
+			rc.Close()
+			continue
+		}
+
+		// fingerprintMember already drained the prefix used for the
+		// fingerprint; reopen to scan the member from the beginning.
+		rc.Close()
+		rc, err = member.open()
+		if err != nil {
+			m.logger.Errorw("failed to reopen archive member", zap.String("path", path), zap.String("member", member.name), zap.Error(err))
+			continue
+		}
+
+		attrs := &FileAttributes{
+			Name: path + archiveMemberSeparator + member.name,
+			Path: path,
+		}
+
+		var source io.Reader = rc
+		if limiter := m.limiterFor(attrs.Name); limiter != nil {
+			source = &rateLimitedReader{Reader: rc, limiter: limiter}
+		}
+		scanAndEmit(ctx, source, m.maxLogSize, m.archiveSplitter, attrs, m.emit)
+		rc.Close()
+	}
+}
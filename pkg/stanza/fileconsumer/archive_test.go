@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func writeTestTarGz(t *testing.T, path string, members map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	for name, content := range members {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+}
+
+func TestIsArchivePath(t *testing.T) {
+	require.True(t, isArchivePath("logs.tar"))
+	require.True(t, isArchivePath("logs.tar.gz"))
+	require.True(t, isArchivePath("logs.gz"))
+	require.False(t, isArchivePath("logs.log"))
+}
+
+func TestReadArchiveEmitsOnePerMember(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "logs.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{
+		"a/one.log": "first\n",
+		"b/two.log": "second\n",
+	})
+
+	var mu sync.Mutex
+	var names []string
+	emit := func(_ context.Context, attrs *FileAttributes, token []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		names = append(names, attrs.Name)
+	}
+
+	cfg := NewConfig()
+	cfg.Include = []string{filepath.Join(dir, "*.tar.gz")}
+	cfg.Archive = true
+	m, err := cfg.NewManager(testutil.Logger(t), emit)
+	require.NoError(t, err)
+	m.consumedArchiveMembers = make(map[string]struct{})
+
+	m.readArchive(context.Background(), archivePath)
+
+	require.ElementsMatch(t, []string{
+		archivePath + archiveMemberSeparator + "a/one.log",
+		archivePath + archiveMemberSeparator + "b/two.log",
+	}, names)
+}
+
+func TestReadArchiveSkipsAlreadyConsumedMembers(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "logs.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"one.log": "only line\n"})
+
+	var count int
+	emit := func(_ context.Context, _ *FileAttributes, _ []byte) {
+		count++
+	}
+
+	cfg := NewConfig()
+	cfg.Include = []string{filepath.Join(dir, "*.tar.gz")}
+	cfg.Archive = true
+	m, err := cfg.NewManager(testutil.Logger(t), emit)
+	require.NoError(t, err)
+	m.consumedArchiveMembers = make(map[string]struct{})
+
+	m.readArchive(context.Background(), archivePath)
+	m.readArchive(context.Background(), archivePath)
+
+	require.Equal(t, 1, count)
+}
+
+func TestReadArchiveAppliesMultilineSplitter(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "logs.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{
+		"one.log": "START first\nmore of first\nSTART second\n",
+	})
+
+	var tokens []string
+	emit := func(_ context.Context, _ *FileAttributes, token []byte) {
+		tokens = append(tokens, string(token))
+	}
+
+	cfg := NewConfig()
+	cfg.Include = []string{filepath.Join(dir, "*.tar.gz")}
+	cfg.Archive = true
+	cfg.Splitter.Multiline.LineStartPattern = "^START"
+	m, err := cfg.NewManager(testutil.Logger(t), emit)
+	require.NoError(t, err)
+	m.consumedArchiveMembers = make(map[string]struct{})
+
+	m.readArchive(context.Background(), archivePath)
+
+	require.Equal(t, []string{"START first\nmore of first", "START second"}, tokens)
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	defaultMaxLogSize         = 1024 * 1024
+	defaultMaxConcurrentFiles = 1024
+	defaultFingerprintSize    = 1000
+
+	// rateLimitScopePerFile gives every tailed file its own independent
+	// byte budget; rateLimitScopeAggregate shares a single budget across
+	// all files handled by the Manager.
+	rateLimitScopePerFile   = "per_file"
+	rateLimitScopeAggregate = "aggregate"
+)
+
+// EmitFunc is called with the attributes and decoded token for every log
+// entry the Manager reads from a tailed file.
+type EmitFunc func(ctx context.Context, attrs *FileAttributes, token []byte)
+
+// Config is the configuration of a fileconsumer Manager.
+type Config struct {
+	Attributes map[string]interface{} `mapstructure:"attributes,omitempty"`
+	Resource   map[string]interface{} `mapstructure:"resource,omitempty"`
+
+	Include []string `mapstructure:"include,omitempty"`
+	Exclude []string `mapstructure:"exclude,omitempty"`
+
+	PollInterval       time.Duration         `mapstructure:"poll_interval,omitempty"`
+	MaxConcurrentFiles int                   `mapstructure:"max_concurrent_files,omitempty"`
+	MaxLogSize         helper.ByteSize       `mapstructure:"max_log_size,omitempty"`
+	FingerprintSize    helper.ByteSize       `mapstructure:"fingerprint_size,omitempty"`
+	Splitter           helper.SplitterConfig `mapstructure:",squash"`
+	StartAt            string                `mapstructure:"start_at,omitempty"`
+	IncludeFileName    bool                  `mapstructure:"include_file_name,omitempty"`
+	IncludeFilePath    bool                  `mapstructure:"include_file_path,omitempty"`
+	ForceFlushPeriod   time.Duration         `mapstructure:"force_flush_period,omitempty"`
+
+	// MaxReadBytesPerSecond caps how fast the Manager consumes bytes from
+	// tailed files during each poll cycle. Zero (the default, and the only
+	// way to represent "unset" with a plain numeric field) disables
+	// throttling entirely, the same convention ForceFlushPeriod already uses
+	// above for its own "off" state; it is not rejected by Build.
+	MaxReadBytesPerSecond helper.ByteSize `mapstructure:"max_read_bytes_per_second,omitempty"`
+	// RateLimitScope selects whether MaxReadBytesPerSecond applies to each
+	// file independently ("per_file", the default) or is shared across all
+	// files read concurrently by the Manager ("aggregate").
+	RateLimitScope string `mapstructure:"rate_limit_scope,omitempty"`
+
+	// Archive, when true, treats any matched path ending in .tar, .tar.gz,
+	// or .gz as a container of virtual files rather than reading the raw
+	// archive bytes: each tar member (or the single decompressed stream of
+	// a plain .gz) is tailed as its own logical file.
+	Archive bool `mapstructure:"archive,omitempty"`
+}
+
+// NewConfig creates a new Config with default values.
+func NewConfig() *Config {
+	return &Config{
+		Include:            []string{},
+		Exclude:            []string{},
+		PollInterval:       200 * time.Millisecond,
+		MaxConcurrentFiles: defaultMaxConcurrentFiles,
+		MaxLogSize:         defaultMaxLogSize,
+		FingerprintSize:    defaultFingerprintSize,
+		Splitter:           helper.NewSplitterConfig(),
+		StartAt:            "end",
+		IncludeFileName:    true,
+		IncludeFilePath:    false,
+		RateLimitScope:     rateLimitScopePerFile,
+	}
+}
+
+// NewManager validates the Config and constructs a ready-to-use Manager.
+// It is not named Build because Config is embedded alongside
+// helper.WriterConfig in operator configs, and both already have a Build
+// method; giving this one a different name keeps that embedding
+// unambiguous.
+func (c Config) NewManager(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error) {
+	if c.MaxLogSize <= 0 {
+		return nil, fmt.Errorf("max_log_size must be positive")
+	}
+	if c.MaxConcurrentFiles <= 0 {
+		return nil, fmt.Errorf("max_concurrent_files must be positive")
+	}
+
+	finder, err := newFinder(c.Include, c.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tailed files may still grow, so their splitter must not flush a
+	// trailing unterminated token at EOF. Archive members, by contrast, are
+	// read exactly once to their true end, so their splitter should flush
+	// whatever's left when it hits EOF.
+	splitter, err := c.Splitter.Build(false, int(c.MaxLogSize))
+	if err != nil {
+		return nil, err
+	}
+	archiveSplitter, err := c.Splitter.Build(true, int(c.MaxLogSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.MaxReadBytesPerSecond < 0 {
+		return nil, fmt.Errorf("max_read_bytes_per_second must not be negative")
+	}
+	switch c.RateLimitScope {
+	case "", rateLimitScopePerFile, rateLimitScopeAggregate:
+		// valid
+	default:
+		return nil, fmt.Errorf("rate_limit_scope must be %q or %q, got %q", rateLimitScopePerFile, rateLimitScopeAggregate, c.RateLimitScope)
+	}
+
+	return &Manager{
+		logger:          logger,
+		emit:            emit,
+		finder:          finder,
+		pollInterval:    c.PollInterval,
+		maxLogSize:      int(c.MaxLogSize),
+		maxBatches:      c.MaxConcurrentFiles,
+		splitter:        splitter,
+		archiveSplitter: archiveSplitter,
+		maxReadBPS:      int64(c.MaxReadBytesPerSecond),
+		rateLimitScope:  c.RateLimitScope,
+		archive:         c.Archive,
+	}, nil
+}
@@ -353,6 +353,40 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "max_read_bytes_per_second_mib_lower",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.MaxReadBytesPerSecond = helper.ByteSize(1048576)
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "max_read_bytes_per_second_kb",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.MaxReadBytesPerSecond = helper.ByteSize(500_000)
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "rate_limit_scope_aggregate",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.MaxReadBytesPerSecond = helper.ByteSize(1048576)
+					cfg.RateLimitScope = "aggregate"
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "archive_enabled",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.Include = append(cfg.Include, "*.tar.gz")
+					cfg.Archive = true
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 		},
 	}.Run(t)
 }
@@ -484,6 +518,78 @@ func TestBuild(t *testing.T) {
 			require.Error,
 			nil,
 		},
+		{
+			"MaxReadBytesPerSecondNegative",
+			func(f *Config) {
+				f.MaxReadBytesPerSecond = -1
+			},
+			require.Error,
+			nil,
+		},
+		{
+			// Zero is the field's default (and the only representable
+			// "unset" value for a plain numeric mapstructure field), so it
+			// disables throttling rather than being rejected; only a
+			// negative value, which can't come from an unset config, is an
+			// error.
+			"MaxReadBytesPerSecondZeroDisablesLimiting",
+			func(f *Config) {
+				f.MaxReadBytesPerSecond = 0
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.Equal(t, int64(0), f.maxReadBPS)
+			},
+		},
+		{
+			"RateLimitScopeAggregate",
+			func(f *Config) {
+				f.MaxReadBytesPerSecond = helper.ByteSize(1024)
+				f.RateLimitScope = "aggregate"
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.Equal(t, rateLimitScopeAggregate, f.rateLimitScope)
+			},
+		},
+		{
+			"InvalidRateLimitScope",
+			func(f *Config) {
+				f.MaxReadBytesPerSecond = helper.ByteSize(1024)
+				f.RateLimitScope = "sometimes"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"ArchiveGlobPattern",
+			func(f *Config) {
+				f.Include = []string{"/var/log/archives/*.tar.gz"}
+				f.Archive = true
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.True(t, f.archive)
+				require.Equal(t, f.finder.Include, []string{"/var/log/archives/*.tar.gz"})
+			},
+		},
+		{
+			"ArchiveWithMultilineAndMaxLogSize",
+			func(f *Config) {
+				f.Include = []string{"/var/log/archives/*.tar"}
+				f.Archive = true
+				f.MaxLogSize = helper.ByteSize(2048)
+				f.Splitter = helper.NewSplitterConfig()
+				f.Splitter.Multiline = helper.MultilineConfig{
+					LineStartPattern: "START.*",
+				}
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.True(t, f.archive)
+				require.Equal(t, 2048, f.maxLogSize)
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -495,7 +601,7 @@ func TestBuild(t *testing.T) {
 
 			nopEmit := func(_ context.Context, _ *FileAttributes, _ []byte) {}
 
-			input, err := cfg.Build(testutil.Logger(t), nopEmit)
+			input, err := cfg.NewManager(testutil.Logger(t), nopEmit)
 			tc.errorRequirement(t, err)
 			if err != nil {
 				return
@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// Manager tails the set of files matched by its Finder and emits the
+// tokens produced by each file's splitter.
+type Manager struct {
+	logger *zap.SugaredLogger
+	emit   EmitFunc
+
+	finder       Finder
+	pollInterval time.Duration
+	maxLogSize   int
+	maxBatches   int
+	// splitter tokenizes and decodes the bytes read from a tailed file, and
+	// archiveSplitter does the same for archive members; see Config.Splitter.
+	// They differ only in flushAtEOF: a tailed file may still grow, so its
+	// splitter withholds a trailing unterminated token in case more data
+	// arrives, while an archive member is read to its true end exactly once.
+	splitter        *helper.Splitter
+	archiveSplitter *helper.Splitter
+
+	// maxReadBPS and rateLimitScope configure the optional read-rate
+	// limiter; see Config.MaxReadBytesPerSecond and Config.RateLimitScope.
+	maxReadBPS         int64
+	rateLimitScope     string
+	aggregateLimiter   *rateLimiter
+	aggregateLimiterMu sync.Mutex
+	// ThroughputObserver, if set before Start, is invoked with the smoothed
+	// bytes/sec observed for each rate-limited file (or "aggregate" when
+	// RateLimitScope is "aggregate") every time a measurement window rolls
+	// over. This is the hook operators can use to export a metric.
+	ThroughputObserver ThroughputObserver
+
+	// archive enables Config.Archive: matched paths ending in .tar,
+	// .tar.gz, or .gz are tailed member-by-member instead of as raw bytes.
+	archive bool
+	// consumedArchiveMembers records the content fingerprint of every
+	// archive member that has been taken up for emission (it is recorded
+	// before the member is read), so that rotated or appended archives
+	// don't re-emit members. Archives are read-once
+	// per fingerprint: live growth of a member is never tracked.
+	consumedArchiveMembers map[string]struct{}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	knownFiles map[string]*Reader
+	mu         sync.Mutex
+}
+
+// Start begins polling the configured paths on the configured interval.
+func (m *Manager) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.knownFiles = make(map[string]*Reader)
+	m.consumedArchiveMembers = make(map[string]struct{})
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.poll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop halts polling and waits for any in-flight reads to finish.
+func (m *Manager) Stop() error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+	return nil
+}
+
+// poll finds the currently matching files and reads any new content from each.
+func (m *Manager) poll(ctx context.Context) {
+	paths, err := m.finder.FindFiles()
+	if err != nil {
+		m.logger.Errorw("failed to find files", zap.Error(err))
+		return
+	}
+
+	sem := make(chan struct{}, m.maxBatches)
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		path := path
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if m.archive && isArchivePath(path) {
+				m.readArchive(ctx, path)
+				return
+			}
+			m.readFile(ctx, path)
+		}()
+	}
+	wg.Wait()
+}
+
+func (m *Manager) readFile(ctx context.Context, path string) {
+	m.mu.Lock()
+	reader, ok := m.knownFiles[path]
+	if !ok {
+		var err error
+		reader, err = newReader(path, m.maxLogSize, m.splitter)
+		if err != nil {
+			m.mu.Unlock()
+			m.logger.Errorw("failed to open file", zap.String("path", path), zap.Error(err))
+			return
+		}
+		reader.limiter = m.limiterFor(path)
+		m.knownFiles[path] = reader
+	}
+	m.mu.Unlock()
+
+	reader.ReadToEnd(ctx, m.emit)
+}
+
+// limiterFor returns the rateLimiter that should throttle reads of path, or
+// nil if no rate limit is configured. In "aggregate" scope every file shares
+// a single lazily-created limiter; in "per_file" scope (the default) each
+// file gets its own.
+func (m *Manager) limiterFor(path string) *rateLimiter {
+	if m.maxReadBPS <= 0 {
+		return nil
+	}
+	if m.rateLimitScope != rateLimitScopeAggregate {
+		return newRateLimiter(m.maxReadBPS, path, m.logger, m.ThroughputObserver)
+	}
+
+	m.aggregateLimiterMu.Lock()
+	defer m.aggregateLimiterMu.Unlock()
+	if m.aggregateLimiter == nil {
+		m.aggregateLimiter = newRateLimiter(m.maxReadBPS, "aggregate", m.logger, m.ThroughputObserver)
+	}
+	return m.aggregateLimiter
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Finder resolves the set of paths that should currently be tailed, given a
+// set of include and exclude glob patterns.
+type Finder struct {
+	Include []string
+	Exclude []string
+}
+
+// newFinder validates the Include/Exclude globs and returns a ready-to-use Finder.
+func newFinder(include, exclude []string) (Finder, error) {
+	for _, pattern := range include {
+		if _, err := filepath.Match(filepath.Base(pattern), ""); err != nil {
+			return Finder{}, fmt.Errorf("parse include glob: %w", err)
+		}
+	}
+	for _, pattern := range exclude {
+		if _, err := filepath.Match(filepath.Base(pattern), ""); err != nil {
+			return Finder{}, fmt.Errorf("parse exclude glob: %w", err)
+		}
+	}
+	return Finder{Include: include, Exclude: exclude}, nil
+}
+
+// FindFiles expands the Include globs, drops any path that also matches an
+// Exclude glob, and returns the resulting sorted, de-duplicated file list.
+func (f Finder) FindFiles() ([]string, error) {
+	seen := make(map[string]struct{})
+	var matches []string
+	for _, include := range f.Include {
+		paths, err := filepath.Glob(include)
+		if err != nil {
+			return nil, fmt.Errorf("glob include pattern %q: %w", include, err)
+		}
+	pathLoop:
+		for _, path := range paths {
+			for _, exclude := range f.Exclude {
+				if ok, _ := filepath.Match(exclude, path); ok {
+					continue pathLoop
+				}
+			}
+			if _, ok := seen[path]; ok {
+				continue
+			}
+			seen[path] = struct{}{}
+			matches = append(matches, path)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// emaSmoothing is the weight given to the most recent window when updating
+// the exponential moving average of observed throughput.
+const emaSmoothing = 0.3
+
+// rateLimiter throttles cumulative bytes read to a configured bytes-per-second
+// ceiling using a simple windowed token bucket, and tracks a smoothed
+// (exponential moving average) view of observed throughput for diagnostics.
+type rateLimiter struct {
+	mu sync.Mutex
+
+	limitBPS    int64
+	windowStart time.Time
+	windowBytes int64
+	emaBPS      float64
+
+	logger   *zap.SugaredLogger
+	observer ThroughputObserver
+	name     string
+}
+
+// ThroughputObserver receives the smoothed bytes/sec observed for a given
+// file (or "aggregate" when RateLimitScope is "aggregate") each time the
+// rate limiter's measurement window rolls over.
+type ThroughputObserver func(name string, bytesPerSecond float64)
+
+func newRateLimiter(limitBPS int64, name string, logger *zap.SugaredLogger, observer ThroughputObserver) *rateLimiter {
+	return &rateLimiter{
+		limitBPS: limitBPS,
+		logger:   logger,
+		observer: observer,
+		name:     name,
+	}
+}
+
+// acquire records n bytes as consumed in the current one-second window and,
+// if the window's total then exceeds the configured rate, sleeps for as long
+// as the excess would take at that rate. A nil receiver or non-positive
+// limit disables throttling entirely.
+func (r *rateLimiter) acquire(n int) {
+	if r == nil || r.limitBPS <= 0 || n <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	if r.windowStart.IsZero() {
+		r.windowStart = now
+	}
+
+	if elapsed := now.Sub(r.windowStart); elapsed >= time.Second {
+		instantBPS := float64(r.windowBytes) / elapsed.Seconds()
+		r.emaBPS = emaSmoothing*instantBPS + (1-emaSmoothing)*r.emaBPS
+		if r.logger != nil {
+			r.logger.Debugw("fileconsumer read throughput",
+				zap.String("name", r.name),
+				zap.Float64("bytes_per_second", r.emaBPS))
+		}
+		if r.observer != nil {
+			r.observer(r.name, r.emaBPS)
+		}
+		r.windowStart = now
+		r.windowBytes = 0
+	}
+
+	r.windowBytes += int64(n)
+	var sleepFor time.Duration
+	if over := r.windowBytes - r.limitBPS; over > 0 {
+		sleepFor = time.Duration(float64(over) / float64(r.limitBPS) * float64(time.Second))
+	}
+	r.mu.Unlock()
+
+	if sleepFor > 0 {
+		time.Sleep(sleepFor)
+	}
+}
+
+// throughput returns the last-measured smoothed bytes/sec, primarily for tests.
+func (r *rateLimiter) throughput() float64 {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.emaBPS
+}
+
+// rateLimitedReader wraps an io.Reader so that every Read call is metered
+// against a shared or per-file rateLimiter before returning to the caller.
+type rateLimitedReader struct {
+	io.Reader
+	limiter *rateLimiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.limiter.acquire(n)
+	}
+	return n, err
+}
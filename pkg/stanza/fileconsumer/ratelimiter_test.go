@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestRateLimiterDisabledByDefault(t *testing.T) {
+	var r *rateLimiter
+	start := time.Now()
+	r.acquire(10 * 1024 * 1024)
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRateLimiterThrottlesAboveLimit(t *testing.T) {
+	r := newRateLimiter(1024, "test", testutil.Logger(t), nil)
+
+	start := time.Now()
+	r.acquire(1024)
+	r.acquire(1024)
+	require.GreaterOrEqual(t, time.Since(start), 1*time.Second)
+}
+
+func TestRateLimiterReportsThroughput(t *testing.T) {
+	var observed float64
+	r := newRateLimiter(1024*1024, "test", testutil.Logger(t), func(name string, bytesPerSecond float64) {
+		observed = bytesPerSecond
+	})
+
+	r.windowStart = time.Now().Add(-2 * time.Second)
+	r.windowBytes = 2048
+	r.acquire(2048)
+
+	require.Greater(t, observed, float64(0))
+	require.Greater(t, r.throughput(), float64(0))
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// Reader tracks the read offset of a single tailed file and emits the
+// tokens produced by scanning any bytes appended since the last read.
+type Reader struct {
+	path       string
+	file       *os.File
+	offset     int64
+	maxLogSize int
+	attrs      *FileAttributes
+	splitter   *helper.Splitter
+
+	// limiter, when non-nil, throttles how fast this reader may consume
+	// bytes from file; see Config.MaxReadBytesPerSecond.
+	limiter *rateLimiter
+}
+
+func newReader(path string, maxLogSize int, splitter *helper.Splitter) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		path:       path,
+		file:       f,
+		maxLogSize: maxLogSize,
+		attrs:      &FileAttributes{Name: path, Path: path},
+		splitter:   splitter,
+	}, nil
+}
+
+// ReadToEnd reads and emits any bytes appended to the file since the last call.
+func (r *Reader) ReadToEnd(ctx context.Context, emit EmitFunc) {
+	if _, err := r.file.Seek(r.offset, 0); err != nil {
+		return
+	}
+
+	var source io.Reader = r.file
+	if r.limiter != nil {
+		source = &rateLimitedReader{Reader: r.file, limiter: r.limiter}
+	}
+
+	scanAndEmit(ctx, source, r.maxLogSize, r.splitter, r.attrs, emit)
+
+	if pos, err := r.file.Seek(0, 1); err == nil {
+		r.offset = pos
+	}
+}
+
+// scanAndEmit tokenizes source using splitter's configured multiline split
+// function (falling back to plain newline splitting if splitter is nil),
+// bounds each token to maxLogSize, decodes it per splitter's EncodingConfig,
+// and emits the result with attrs attached. It is shared by the regular
+// tailing path and the archive-member path so that a given Config.Splitter
+// and max_log_size are enforced identically regardless of where the bytes
+// came from.
+func scanAndEmit(ctx context.Context, source io.Reader, maxLogSize int, splitter *helper.Splitter, attrs *FileAttributes, emit EmitFunc) {
+	scanner := bufio.NewScanner(source)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogSize)
+	if splitter != nil && splitter.SplitFunc != nil {
+		scanner.Split(splitter.SplitFunc)
+	}
+	for scanner.Scan() {
+		token := scanner.Bytes()
+		if splitter != nil {
+			if decoded, err := splitter.Encoding.Decode(token); err == nil {
+				token = decoded
+			}
+		}
+		emit(ctx, attrs, token)
+	}
+}
+
+// Close releases the underlying file handle.
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// mockOperatorConfig wraps a fileconsumer Config with the boilerplate that
+// operatortest.ConfigUnmarshalTests needs in order to unmarshal it as a
+// standalone operator config.
+type mockOperatorConfig struct {
+	helper.WriterConfig `mapstructure:",squash"`
+	Config              `mapstructure:",squash"`
+}
+
+func newMockOperatorConfig(cfg *Config) *mockOperatorConfig {
+	return &mockOperatorConfig{
+		WriterConfig: helper.NewWriterConfig("test_id", "test_type"),
+		Config:       *cfg,
+	}
+}